@@ -0,0 +1,71 @@
+package ispcdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+
+	"github.com/osphost/ispconfig-traefik-dnschallenge/internal"
+)
+
+// findZone walks fqdn's labels from the most to the least specific,
+// querying ISPConfig's dns_zone_get for each candidate parent, and
+// returns the first one ISPConfig actually knows about along with the
+// subdomain of fqdn relative to it. This is required for multi-label
+// TLDs (.co.uk, .com.au) and for zones delegated below the SLD, where a
+// fixed "last two labels" split picks the wrong zone.
+//
+// The fqdn -> zone mapping is cached for the lifetime of the DNSProvider.
+func (d *DNSProvider) findZone(ctx context.Context, fqdn string) (*internal.Zone, string, error) {
+	fqdn = dns01.ToFqdn(fqdn)
+
+	d.zoneCacheMu.Lock()
+	zone, cached := d.zoneCache[fqdn]
+	d.zoneCacheMu.Unlock()
+
+	if !cached {
+		var err error
+		for _, candidate := range candidateZones(fqdn) {
+			zone, err = d.client.GetZone(ctx, candidate)
+			if err != nil {
+				return nil, "", err
+			}
+			if zone != nil {
+				clientID, err := d.client.GetClientID(ctx, zone.SysUserID)
+				if err != nil {
+					return nil, "", err
+				}
+				zone.ClientID = clientID
+				break
+			}
+		}
+		if zone == nil {
+			return nil, "", fmt.Errorf("no zone found in ISPConfig for %s", fqdn)
+		}
+
+		d.zoneCacheMu.Lock()
+		d.zoneCache[fqdn] = zone
+		d.zoneCacheMu.Unlock()
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(fqdn, zone.Origin)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return zone, subDomain, nil
+}
+
+// candidateZones returns fqdn's ancestor domains, most specific first,
+// stopping one label short of the root so a bare TLD is never queried.
+func candidateZones(fqdn string) []string {
+	labels := strings.Split(dns01.UnFqdn(fqdn), ".")
+
+	candidates := make([]string, 0, len(labels)-1)
+	for i := 0; i < len(labels)-1; i++ {
+		candidates = append(candidates, dns01.ToFqdn(strings.Join(labels[i:], ".")))
+	}
+	return candidates
+}
@@ -0,0 +1,41 @@
+package ispcdns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCandidateZones(t *testing.T) {
+	tests := []struct {
+		fqdn string
+		want []string
+	}{
+		{
+			fqdn: "_acme-challenge.www.example.com.",
+			want: []string{
+				"_acme-challenge.www.example.com.",
+				"www.example.com.",
+				"example.com.",
+			},
+		},
+		{
+			fqdn: "example.com.",
+			want: []string{
+				"example.com.",
+			},
+		},
+		{
+			fqdn: "com.",
+			want: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fqdn, func(t *testing.T) {
+			got := candidateZones(test.fqdn)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("candidateZones(%q) = %v, want %v", test.fqdn, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Command ispcdns-hook is a thin CLI wrapper around the ispcdns package,
+// for use as a Traefik ACME DNS challenge hook.
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/osphost/ispconfig-traefik-dnschallenge"
+)
+
+var ISPCLogPath = os.Getenv("ISPC_Log_Path")
+
+func createLogger() *zap.Logger {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.TimeKey = "timestamp"
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	dirPath := ISPCLogPath + "/"
+
+	// Create the directory and its parent directories if they don't exist
+	err := os.MkdirAll(dirPath, 0755)
+	if err != nil {
+		fmt.Println("Error creating directory:", err)
+		os.Exit(1)
+	}
+
+	config := zap.Config{
+		Level:             zap.NewAtomicLevelAt(zap.InfoLevel),
+		Development:       false,
+		DisableCaller:     false,
+		DisableStacktrace: false,
+		Sampling:          nil,
+		Encoding:          "json",
+		EncoderConfig:     encoderCfg,
+		OutputPaths: []string{
+			"stderr",
+			dirPath + "/" + time.Now().Format("2006-01-02") + ".log",
+		},
+		ErrorOutputPaths: []string{
+			"stderr",
+		},
+		InitialFields: map[string]interface{}{
+			"pid": os.Getpid(),
+		},
+	}
+
+	return zap.Must(config.Build())
+}
+
+func main() {
+	logger := createLogger()
+
+	args := os.Args
+	if len(args) < 3 {
+		logger.Fatal("Invalid number of arguments", zap.Int("minimum", 2), zap.Int("provided", len(args)))
+	}
+
+	action := args[1]
+	domain := args[2]
+	if len(args) < 4 {
+		logger.Fatal("Not enough arguments", zap.Int("minimum", 3), zap.Int("provided", len(args)))
+	}
+	value := args[3]
+
+	if domain == "" {
+		logger.Fatal("Empty domain")
+	}
+
+	provider, err := ispcdns.NewDNSProvider()
+	if err != nil {
+		logger.Fatal("Failed to create DNS provider", zap.Error(err))
+	}
+
+	switch action {
+	case "present":
+		if err := provider.PresentRecord(domain, value); err != nil {
+			logger.Fatal("Failed to add record", zap.Error(err))
+		}
+
+		logger.Info("Created TXT record", zap.String("domain", domain))
+		return
+	case "cleanup":
+		if err := provider.CleanupRecord(domain, value); err != nil {
+			logger.Fatal("Failed to remove record", zap.Error(err))
+		}
+		logger.Info("Removed TXT record")
+	}
+}
@@ -0,0 +1,73 @@
+package ispcdns
+
+import (
+	"net/http"
+	"os"
+	"time"
+)
+
+// Environment variables used to populate a default Config.
+const (
+	envNamespace = "ISPCDNS_"
+
+	EnvUsername = envNamespace + "USERNAME"
+	EnvPassword = envNamespace + "PASSWORD"
+	EnvAPIURL   = envNamespace + "API_URL"
+
+	EnvSequenceInterval = envNamespace + "SEQUENCE_INTERVAL"
+)
+
+const (
+	defaultTTL                = 3600
+	defaultPropagationTimeout = 2 * time.Minute
+	defaultPollingInterval    = 4 * time.Second
+	defaultHTTPTimeout        = 30 * time.Second
+	defaultSequenceInterval   = 2 * time.Minute
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Username string
+	Password string
+	APIURL   string
+
+	TTL                int
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+
+	// SequenceInterval is returned by DNSProvider.Sequential, which tells
+	// lego to resolve every DNS-01 challenge using this provider one at a
+	// time instead of concurrently. See DNSProvider.Sequential.
+	SequenceInterval time.Duration
+
+	HTTPClient *http.Client
+}
+
+// NewDefaultConfig returns a Config instance populated from the ISPCDNS_*
+// environment variables, with sane defaults for everything else.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Username: os.Getenv(EnvUsername),
+		Password: os.Getenv(EnvPassword),
+		APIURL:   os.Getenv(EnvAPIURL),
+
+		TTL:                defaultTTL,
+		PropagationTimeout: defaultPropagationTimeout,
+		PollingInterval:    defaultPollingInterval,
+		SequenceInterval:   sequenceIntervalFromEnv(),
+		HTTPClient: &http.Client{
+			Timeout: defaultHTTPTimeout,
+		},
+	}
+}
+
+// sequenceIntervalFromEnv parses EnvSequenceInterval, falling back to
+// defaultSequenceInterval if it's unset or not a valid duration.
+func sequenceIntervalFromEnv() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv(EnvSequenceInterval))
+	if err != nil {
+		return defaultSequenceInterval
+	}
+
+	return interval
+}
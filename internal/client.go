@@ -0,0 +1,247 @@
+// Package internal implements a typed, retrying client for the
+// ISPConfig remote API, following the pattern used by the hosttech and
+// checkdomain lego DNS providers.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	maxAttempts        = 4
+	initialBackoff     = 250 * time.Millisecond
+)
+
+// Client is an ISPConfig remote API client. It logs in lazily on the
+// first authenticated call and re-logs in automatically if ISPConfig
+// reports the cached session as invalid or expired.
+type Client struct {
+	HTTPClient *http.Client
+
+	baseURL  string
+	username string
+	password string
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewClient returns a Client for the ISPConfig remote API at baseURL.
+func NewClient(baseURL, username, password string) *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: defaultHTTPTimeout},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+	}
+}
+
+// GetZone looks up the zone whose origin is exactly origin, returning nil
+// (no error) if ISPConfig doesn't host it.
+func (c *Client) GetZone(ctx context.Context, origin string) (*Zone, error) {
+	zones, err := callAuthenticated[[]Zone](ctx, c, "dns_zone_get", func(sessionID string) any {
+		return zoneGetRequest{SessionID: sessionID, PrimaryID: zoneGetPrimary{Origin: origin}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) == 0 || zones[0].ID == "" {
+		return nil, nil
+	}
+
+	zone := zones[0]
+	zone.Origin = origin
+
+	return &zone, nil
+}
+
+// GetClientID returns the ISPConfig client ID owning sysUserID.
+func (c *Client) GetClientID(ctx context.Context, sysUserID string) (int, error) {
+	return callAuthenticated[int](ctx, c, "client_get_id", func(sessionID string) any {
+		return clientGetIDRequest{SessionID: sessionID, SysUserID: sysUserID}
+	})
+}
+
+// AddTXTRecord creates a TXT record under clientID and returns its ID.
+func (c *Client) AddTXTRecord(ctx context.Context, clientID int, params TXTParams) (string, error) {
+	return callAuthenticated[string](ctx, c, "dns_txt_add", func(sessionID string) any {
+		return txtAddRequest{SessionID: sessionID, ClientID: clientID, Params: params, UpdateSerial: true}
+	})
+}
+
+// GetTXTRecords returns every TXT record with the given record name in
+// zoneID. Scoping by zone keeps two zones that happen to share a relative
+// record name (e.g. "_acme-challenge") from matching each other's records.
+func (c *Client) GetTXTRecords(ctx context.Context, zoneID, name string) ([]TXTRecord, error) {
+	return callAuthenticated[[]TXTRecord](ctx, c, "dns_txt_get", func(sessionID string) any {
+		return txtGetRequest{SessionID: sessionID, PrimaryID: txtGetPrimary{Zone: zoneID, Name: name, Type: "TXT"}}
+	})
+}
+
+// DeleteTXTRecord deletes the TXT record identified by recordID.
+func (c *Client) DeleteTXTRecord(ctx context.Context, recordID string) error {
+	_, err := callAuthenticated[json.RawMessage](ctx, c, "dns_txt_delete", func(sessionID string) any {
+		return txtDeleteRequest{SessionID: sessionID, PrimaryID: recordID, UpdateSerial: true}
+	})
+	return err
+}
+
+// Logout invalidates the cached session, if any.
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	sessionID := c.sessionID
+	c.sessionID = ""
+	c.mu.Unlock()
+
+	if sessionID == "" {
+		return nil
+	}
+
+	_, err := do[json.RawMessage](ctx, c, "logout", logoutRequest{SessionID: sessionID})
+	return err
+}
+
+func (c *Client) ensureSession(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessionID != "" {
+		return c.sessionID, nil
+	}
+
+	sessionID, err := do[string](ctx, c, "login", loginRequest{
+		Username:    c.username,
+		Password:    c.password,
+		ClientLogin: false,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	c.sessionID = strings.Trim(sessionID, `"`)
+
+	return c.sessionID, nil
+}
+
+func (c *Client) refreshSession(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	c.sessionID = ""
+	c.mu.Unlock()
+
+	return c.ensureSession(ctx)
+}
+
+// callAuthenticated runs an authenticated RPC call, logging in first if
+// needed, and retries once with a fresh session if ISPConfig rejects the
+// cached one as invalid or expired.
+func callAuthenticated[T any](ctx context.Context, c *Client, endpoint string, buildReq func(sessionID string) any) (T, error) {
+	var zero T
+
+	sessionID, err := c.ensureSession(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	result, err := do[T](ctx, c, endpoint, buildReq(sessionID))
+	if isSessionError(err) {
+		sessionID, err = c.refreshSession(ctx)
+		if err != nil {
+			return zero, err
+		}
+		result, err = do[T](ctx, c, endpoint, buildReq(sessionID))
+	}
+
+	return result, err
+}
+
+// do performs a single ISPConfig RPC call, decoding its {code, message,
+// response} envelope into T and surfacing message as the error when code
+// isn't "ok". 5xx responses are retried with exponential backoff.
+func do[T any](ctx context.Context, c *Client, endpoint string, payload any) (T, error) {
+	var zero T
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return zero, fmt.Errorf("ispcdns: marshal %s request: %w", endpoint, err)
+	}
+
+	var envelope apiEnvelope[T]
+
+	err = withRetry(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"?"+endpoint, bytes.NewReader(jsonData))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			return &APIError{Endpoint: endpoint, Code: "session_expired", Message: "session expired or unauthorized"}
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return retryableError{status: resp.StatusCode}
+		}
+
+		envelope = apiEnvelope[T]{}
+
+		return json.NewDecoder(resp.Body).Decode(&envelope)
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	if envelope.Code != "ok" {
+		return zero, &APIError{Endpoint: endpoint, Code: envelope.Code, Message: envelope.Message}
+	}
+
+	return envelope.Response, nil
+}
+
+func withRetry(ctx context.Context, op func() error) error {
+	backoff := initialBackoff
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err = op()
+
+		var retryable retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt == maxAttempts-1 {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return err
+}
+
+func isSessionError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	msg := strings.ToLower(apiErr.Message)
+
+	return strings.Contains(msg, "session") || strings.Contains(msg, "login")
+}
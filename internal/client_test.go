@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newStubServer returns a test server that decodes each request's JSON body
+// and replies with responses[endpoint] (the request's raw query string).
+func newStubServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+
+	var requests []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := r.URL.RawQuery
+
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request body for %s: %v", endpoint, err)
+		}
+		requests = append(requests, map[string]any{"endpoint": endpoint, "body": body})
+
+		resp, ok := responses[endpoint]
+		if !ok {
+			t.Fatalf("unexpected request to endpoint %q", endpoint)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resp))
+	}))
+
+	return server, &requests
+}
+
+func TestGetTXTRecords_RequestShape(t *testing.T) {
+	server, requests := newStubServer(t, map[string]string{
+		"login":       `{"code":"ok","message":"","response":"sess-123"}`,
+		"dns_txt_get": `{"code":"ok","message":"","response":[{"id":"42","name":"_acme-challenge","data":"abc"}]}`,
+	})
+	defer server.Close()
+
+	client := NewClient(server.URL, "user", "pass")
+
+	records, err := client.GetTXTRecords(context.Background(), "7", "_acme-challenge")
+	if err != nil {
+		t.Fatalf("GetTXTRecords: %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "42" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	var getReq map[string]any
+	for _, req := range *requests {
+		if req["endpoint"] == "dns_txt_get" {
+			getReq = req["body"].(map[string]any)
+		}
+	}
+	if getReq == nil {
+		t.Fatal("no dns_txt_get request observed")
+	}
+
+	primaryID, ok := getReq["primary_id"].(map[string]any)
+	if !ok {
+		t.Fatalf("primary_id is not an object: %+v", getReq)
+	}
+
+	// Pin the request shape: primary_id must scope the lookup by zone, not
+	// just by the bare record name, so two zones sharing a relative record
+	// name (e.g. "_acme-challenge") can't cross-match each other's records.
+	if primaryID["zone"] != "7" {
+		t.Errorf("primary_id.zone = %v, want 7", primaryID["zone"])
+	}
+	if primaryID["name"] != "_acme-challenge" {
+		t.Errorf("primary_id.name = %v, want _acme-challenge", primaryID["name"])
+	}
+	if primaryID["type"] != "TXT" {
+		t.Errorf("primary_id.type = %v, want TXT", primaryID["type"])
+	}
+}
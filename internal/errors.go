@@ -0,0 +1,24 @@
+package internal
+
+import "fmt"
+
+// APIError is returned when ISPConfig answers a request with an envelope
+// whose code is not "ok".
+type APIError struct {
+	Endpoint string
+	Code     string
+	Message  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (code=%s)", e.Endpoint, e.Message, e.Code)
+}
+
+// retryableError marks an error as worth retrying with backoff.
+type retryableError struct {
+	status int
+}
+
+func (e retryableError) Error() string {
+	return fmt.Sprintf("server error: status %d", e.status)
+}
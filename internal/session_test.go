@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsSessionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "session expired",
+			err:  &APIError{Endpoint: "dns_txt_add", Code: "session_expired", Message: "session expired or unauthorized"},
+			want: true,
+		},
+		{
+			name: "login required",
+			err:  &APIError{Endpoint: "dns_txt_add", Code: "err", Message: "login failed, please authenticate again"},
+			want: true,
+		},
+		{
+			name: "unrelated API error",
+			err:  &APIError{Endpoint: "dns_txt_add", Code: "err", Message: "zone not found"},
+			want: false,
+		},
+		{
+			name: "non-API error",
+			err:  errors.New("connection reset by peer"),
+			want: false,
+		},
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isSessionError(test.err); got != test.want {
+				t.Errorf("isSessionError(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+// apiEnvelope is the {code, message, response} shape every ISPConfig
+// remote API call wraps its payload in.
+type apiEnvelope[T any] struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Response T      `json:"response"`
+}
+
+type loginRequest struct {
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	ClientLogin bool   `json:"client_login"`
+}
+
+type logoutRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+type zoneGetRequest struct {
+	SessionID string         `json:"session_id"`
+	PrimaryID zoneGetPrimary `json:"primary_id"`
+}
+
+type zoneGetPrimary struct {
+	Origin string `json:"origin"`
+}
+
+// Zone is an ISPConfig DNS zone, as returned by dns_zone_get. ClientID is
+// not part of that response; it's filled in from a separate
+// client_get_id lookup keyed on SysUserID.
+type Zone struct {
+	ID        string `json:"id"`
+	ServerID  string `json:"server_id"`
+	SysUserID string `json:"sys_userid"`
+	Origin    string `json:"origin"`
+	ClientID  int    `json:"-"`
+}
+
+type clientGetIDRequest struct {
+	SessionID string `json:"session_id"`
+	SysUserID string `json:"sys_userid"`
+}
+
+type txtAddRequest struct {
+	SessionID    string    `json:"session_id"`
+	ClientID     int       `json:"client_id"`
+	Params       TXTParams `json:"params"`
+	UpdateSerial bool      `json:"update_serial"`
+}
+
+// TXTParams are the dns_txt_add record fields.
+type TXTParams struct {
+	ServerID string `json:"server_id"`
+	Zone     string `json:"zone"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Data     string `json:"data"`
+	Aux      string `json:"aux"`
+	TTL      string `json:"ttl"`
+	Active   string `json:"active"`
+	Stamp    string `json:"stamp"`
+	Serial   string `json:"serial"`
+}
+
+type txtGetRequest struct {
+	SessionID string        `json:"session_id"`
+	PrimaryID txtGetPrimary `json:"primary_id"`
+}
+
+type txtGetPrimary struct {
+	Zone string `json:"zone"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TXTRecord is an ISPConfig DNS TXT record, as returned by dns_txt_get.
+type TXTRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+type txtDeleteRequest struct {
+	SessionID    string `json:"session_id"`
+	PrimaryID    string `json:"primary_id"`
+	UpdateSerial bool   `json:"update_serial"`
+}
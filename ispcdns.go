@@ -0,0 +1,265 @@
+// Package ispcdns implements a DNS provider for solving the DNS-01
+// challenge using an ISPConfig server's remote API.
+//
+// It exposes a DNSProvider implementing the go-acme/lego
+// challenge.Provider interface so it can be imported directly by lego,
+// Traefik, Caddy's ACME modules, or a cert-manager webhook shim, instead
+// of being shelled out to as a CLI.
+package ispcdns
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+
+	"github.com/osphost/ispconfig-traefik-dnschallenge/internal"
+)
+
+var _ challenge.Provider = (*DNSProvider)(nil)
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	zoneCacheMu sync.Mutex
+	zoneCache   map[string]*internal.Zone // fqdn -> zone, cached for the process lifetime
+
+	zoneLocksMu sync.Mutex
+	zoneLocks   map[string]*sync.Mutex // zone ID -> mutex serializing its dns_txt_add/dns_txt_delete calls
+
+	recordIDsMu sync.Mutex
+	recordIDs   map[string]string // challenge token -> record ID, so CleanUp can delete by ID without a lookup round-trip
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for ISPConfig.
+// Credentials are read from the ISPCDNS_USERNAME, ISPCDNS_PASSWORD and
+// ISPCDNS_API_URL environment variables.
+func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+	if config.Username == "" || config.Password == "" || config.APIURL == "" {
+		return nil, fmt.Errorf("ispcdns: some credentials information are missing: %s, %s, %s", EnvUsername, EnvPassword, EnvAPIURL)
+	}
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig returns a DNSProvider instance configured with a custom configuration.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("ispcdns: the configuration of the DNS provider is nil")
+	}
+
+	if config.Username == "" || config.Password == "" {
+		return nil, fmt.Errorf("ispcdns: credentials missing")
+	}
+
+	if config.APIURL == "" {
+		return nil, fmt.Errorf("ispcdns: API URL is missing")
+	}
+
+	client := internal.NewClient(config.APIURL, config.Username, config.Password)
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	return &DNSProvider{
+		config:    config,
+		client:    client,
+		zoneCache: make(map[string]*internal.Zone),
+		zoneLocks: make(map[string]*sync.Mutex),
+		recordIDs: make(map[string]string),
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Sequential indicates every DNS-01 challenge using this provider should be
+// resolved one at a time. ISPConfig serializes zone updates via the SOA
+// serial, so concurrent dns_txt_add calls for the same zone with
+// update_serial can race and one record silently loses its serial bump.
+func (d *DNSProvider) Sequential() time.Duration {
+	return d.config.SequenceInterval
+}
+
+// lockZone returns an unlock func for zoneID's mutex, creating it on first use.
+func (d *DNSProvider) lockZone(zoneID string) func() {
+	d.zoneLocksMu.Lock()
+	lock, ok := d.zoneLocks[zoneID]
+	if !ok {
+		lock = &sync.Mutex{}
+		d.zoneLocks[zoneID] = lock
+	}
+	d.zoneLocksMu.Unlock()
+
+	lock.Lock()
+
+	return lock.Unlock
+}
+
+// Present creates a TXT record to fulfil the DNS-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	recordID, err := d.addTXTRecord(context.Background(), info.FQDN, info.Value)
+
+	// Cache the record ID whenever the record was actually created, even if
+	// addTXTRecord went on to fail while waiting for propagation. Otherwise
+	// CleanUp has no way to find and delete it, leaving it orphaned in
+	// ISPConfig and causing a retried Present to add a duplicate record.
+	if recordID != "" {
+		d.recordIDsMu.Lock()
+		d.recordIDs[token] = recordID
+		d.recordIDsMu.Unlock()
+	}
+
+	if err != nil {
+		return fmt.Errorf("ispcdns: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+	ctx := context.Background()
+
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
+	if ok {
+		zone, _, err := d.findZone(ctx, info.FQDN)
+		if err != nil {
+			return fmt.Errorf("ispcdns: %w", err)
+		}
+
+		unlock := d.lockZone(zone.ID)
+		err = d.client.DeleteTXTRecord(ctx, recordID)
+		unlock()
+		if err != nil {
+			return fmt.Errorf("ispcdns: %w", err)
+		}
+
+		return nil
+	}
+
+	// No cached record ID for this token (e.g. a fresh process): fall back
+	// to looking the record up by its expected value.
+	if err := d.deleteTXTRecordByValue(ctx, info.FQDN, info.Value); err != nil {
+		return fmt.Errorf("ispcdns: %w", err)
+	}
+
+	return nil
+}
+
+// PresentRecord creates a TXT record for the literal fqdn/value pair.
+// Present derives the same pair from an apex domain via dns01.GetChallengeInfo
+// and shares its implementation (addTXTRecord), but unlike PresentRecord it
+// also caches the record ID for CleanUp. The ispcdns-hook command calls
+// PresentRecord directly, since Traefik's
+// exec hook already invokes it with the fully-qualified challenge name.
+func (d *DNSProvider) PresentRecord(fqdn, value string) error {
+	_, err := d.addTXTRecord(context.Background(), fqdn, value)
+	if err != nil {
+		return fmt.Errorf("ispcdns: %w", err)
+	}
+
+	return nil
+}
+
+// CleanupRecord removes the TXT record for the literal fqdn/value pair,
+// matching on the record's data so that it doesn't delete a TXT record
+// belonging to a different, concurrently-issued challenge for the same
+// name (e.g. a wildcard and its apex, or a SAN batch). See PresentRecord.
+func (d *DNSProvider) CleanupRecord(fqdn, value string) error {
+	if err := d.deleteTXTRecordByValue(context.Background(), fqdn, value); err != nil {
+		return fmt.Errorf("ispcdns: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) addTXTRecord(ctx context.Context, fqdn, value string) (string, error) {
+	zone, subDomain, err := d.findZone(ctx, fqdn)
+	if err != nil {
+		return "", err
+	}
+
+	params := internal.TXTParams{
+		ServerID: zone.ServerID,
+		Zone:     zone.ID,
+		Name:     subDomain,
+		Type:     "txt",
+		Data:     value,
+		Aux:      "0",
+		TTL:      strconv.Itoa(d.config.TTL),
+		Active:   "y",
+		Stamp:    time.Now().Format("2006-01-02 15:04:05"),
+		Serial:   strconv.FormatInt(time.Now().Unix(), 10),
+	}
+
+	unlock := d.lockZone(zone.ID)
+	recordID, err := d.client.AddTXTRecord(ctx, zone.ClientID, params)
+	unlock()
+	if err != nil {
+		return "", err
+	}
+
+	// Return recordID alongside a propagation error rather than discarding
+	// it: the record was still created, and the caller needs the ID to
+	// clean up the now-orphaned record instead of adding a duplicate on retry.
+	if err := d.waitForPropagation(fqdn, value, zone.Origin); err != nil {
+		return recordID, err
+	}
+
+	return recordID, nil
+}
+
+// deleteTXTRecordByValue deletes the TXT record at fqdn whose data matches
+// value, leaving any other TXT records under the same name untouched.
+func (d *DNSProvider) deleteTXTRecordByValue(ctx context.Context, fqdn, value string) error {
+	zone, subDomain, err := d.findZone(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	records, err := d.client.GetTXTRecords(ctx, zone.ID, subDomain)
+	if err != nil {
+		return err
+	}
+
+	recordID, ok := matchRecordByValue(records, value)
+	if !ok {
+		return fmt.Errorf("no TXT record found for %s with the expected value", subDomain)
+	}
+
+	unlock := d.lockZone(zone.ID)
+	err = d.client.DeleteTXTRecord(ctx, recordID)
+	unlock()
+
+	return err
+}
+
+// matchRecordByValue returns the ID of the first record in records whose
+// Data matches value, so callers delete the record for their own challenge
+// rather than any other record that happens to share the same name.
+func matchRecordByValue(records []internal.TXTRecord, value string) (string, bool) {
+	for _, record := range records {
+		if record.Data == value {
+			return record.ID, true
+		}
+	}
+
+	return "", false
+}
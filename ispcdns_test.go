@@ -0,0 +1,27 @@
+package ispcdns
+
+import (
+	"testing"
+
+	"github.com/osphost/ispconfig-traefik-dnschallenge/internal"
+)
+
+func TestMatchRecordByValue(t *testing.T) {
+	records := []internal.TXTRecord{
+		{ID: "1", Name: "_acme-challenge", Data: "other-challenge-value"},
+		{ID: "2", Name: "_acme-challenge", Data: "our-challenge-value"},
+	}
+
+	id, ok := matchRecordByValue(records, "our-challenge-value")
+	if !ok || id != "2" {
+		t.Errorf("matchRecordByValue() = (%q, %v), want (\"2\", true)", id, ok)
+	}
+
+	if _, ok := matchRecordByValue(records, "nonexistent-value"); ok {
+		t.Error("matchRecordByValue() found a match for a value that isn't present")
+	}
+
+	if _, ok := matchRecordByValue(nil, "anything"); ok {
+		t.Error("matchRecordByValue() found a match against an empty record list")
+	}
+}
@@ -0,0 +1,65 @@
+package ispcdns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+)
+
+// waitForPropagation polls the zone's authoritative nameservers directly
+// for the TXT record at fqdn until every one of them answers with value,
+// rather than trusting ISPConfig's write to have propagated.
+func (d *DNSProvider) waitForPropagation(fqdn, value, zoneOrigin string) error {
+	nameservers, err := net.LookupNS(dns01.UnFqdn(zoneOrigin))
+	if err != nil {
+		return fmt.Errorf("lookup NS records for %s: %w", zoneOrigin, err)
+	}
+	if len(nameservers) == 0 {
+		return fmt.Errorf("no NS records found for %s", zoneOrigin)
+	}
+
+	deadline := time.Now().Add(d.config.PropagationTimeout)
+	for {
+		if allNameserversHaveRecord(nameservers, fqdn, value) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s to propagate to the authoritative nameservers of %s", fqdn, zoneOrigin)
+		}
+
+		time.Sleep(d.config.PollingInterval)
+	}
+}
+
+func allNameserversHaveRecord(nameservers []*net.NS, fqdn, value string) bool {
+	for _, ns := range nameservers {
+		if !nameserverHasRecord(ns.Host, fqdn, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func nameserverHasRecord(nameserver, fqdn, value string) bool {
+	msg := new(dns.Msg)
+	msg.SetQuestion(fqdn, dns.TypeTXT)
+	msg.RecursionDesired = false
+
+	in, err := dns.Exchange(msg, net.JoinHostPort(dns01.UnFqdn(nameserver), "53"))
+	if err != nil {
+		return false
+	}
+
+	for _, rr := range in.Answer {
+		if txt, ok := rr.(*dns.TXT); ok && strings.Join(txt.Txt, "") == value {
+			return true
+		}
+	}
+
+	return false
+}